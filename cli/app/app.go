@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -22,40 +23,74 @@ import (
 //		Usage:  "List containers",
 //		Action: app.WithProject(factory, app.ProjectPs),
 //	}
-type ProjectAction func(project project.APIProject, c *cli.Context)
+type ProjectAction func(ctx context.Context, project project.APIProject, c *cli.Context, output Output)
 
 // BeforeApp is an action that is executed before any cli command.
 func BeforeApp(c *cli.Context) error {
 	if c.GlobalBool("verbose") {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
+	if _, err := ParseOutputFormat(c.GlobalString("format")); err != nil {
+		return err
+	}
 	logrus.Warning("Note: This is an experimental alternate implementation of the Compose CLI (https://github.com/docker/compose)")
 	return nil
 }
 
 // WithProject is a helper function to create a cli.Command action with a ProjectFactory.
-func WithProject(factory ProjectFactory, action ProjectAction) func(context *cli.Context) {
-	return func(context *cli.Context) {
-		p, err := factory.Create(context)
+//
+// It builds a context.Context that is canceled on SIGINT/SIGTERM, and
+// bounded by --timeout when set, so that a Ctrl-C (or a deadline) during
+// an action cancels any in-flight docker API calls instead of waiting for
+// them to return on their own.
+func WithProject(factory ProjectFactory, action ProjectAction) func(c *cli.Context) {
+	return func(c *cli.Context) {
+		p, err := factory.Create(c)
 		if err != nil {
 			logrus.Fatalf("Failed to read project: %v", err)
 		}
-		action(p, context)
+		format, err := ParseOutputFormat(c.GlobalString("format"))
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		output := NewOutput(format, os.Stdout, os.Stderr)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if timeout := c.GlobalDuration("timeout"); timeout > 0 {
+			var timeoutCancel context.CancelFunc
+			ctx, timeoutCancel = context.WithTimeout(ctx, timeout)
+			defer timeoutCancel()
+		}
+
+		signalChan := make(chan os.Signal, 1)
+		signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(signalChan)
+		go func() {
+			select {
+			case <-signalChan:
+				fmt.Fprintln(output.Info(), "\nGracefully stopping...")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		action(ctx, p, c, output)
 	}
 }
 
 // ProjectPs lists the containers.
-func ProjectPs(p project.APIProject, c *cli.Context) {
+func ProjectPs(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	qFlag := c.Bool("q")
-	allInfo, err := p.Ps(qFlag, c.Args()...)
+	allInfo, err := p.Ps(ctx, qFlag, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	os.Stdout.WriteString(allInfo.String(!qFlag))
+	fmt.Fprint(output.Out(), renderInfo(output.Format(), allInfo.String(!qFlag)))
 }
 
 // ProjectPort prints the public port for a port binding.
-func ProjectPort(p project.APIProject, c *cli.Context) {
+func ProjectPort(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	if len(c.Args()) != 2 {
 		logrus.Fatalf("Please pass arguments in the form: SERVICE PORT")
 	}
@@ -65,96 +100,95 @@ func ProjectPort(p project.APIProject, c *cli.Context) {
 	serviceName := c.Args()[0]
 	privatePort := c.Args()[1]
 
-	port, err := p.Port(index, protocol, serviceName, privatePort)
+	port, err := p.Port(ctx, index, protocol, serviceName, privatePort)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	fmt.Println(port)
+	fmt.Fprintln(output.Out(), port)
 }
 
 // ProjectStop stops all services.
-func ProjectStop(p project.APIProject, c *cli.Context) {
-	err := p.Stop(c.Int("timeout"), c.Args()...)
+func ProjectStop(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Stop(ctx, c.Int("timeout"), c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.stop", map[string]interface{}{"services": c.Args()})
 }
 
 // ProjectDown brings all services down (stops and clean containers).
-func ProjectDown(p project.APIProject, c *cli.Context) {
+func ProjectDown(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	options := options.Down{
 		RemoveVolume: c.Bool("v"),
 	}
-	err := p.Down(options, c.Args()...)
+	err := p.Down(ctx, options, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.stop", map[string]interface{}{"services": c.Args()})
 }
 
 // ProjectBuild builds or rebuilds services.
-func ProjectBuild(p project.APIProject, c *cli.Context) {
+func ProjectBuild(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	config := options.Build{
-		NoCache: c.Bool("no-cache"),
+		NoCache:         c.Bool("no-cache"),
+		ProgressPrinter: resolveBuildProgressPrinter(c.String("progress"), output),
 	}
-	err := p.Build(config, c.Args()...)
+	output.Event("build.progress", map[string]interface{}{"services": c.Args(), "state": "start"})
+	err := p.Build(ctx, config, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("build.progress", map[string]interface{}{"services": c.Args(), "state": "done"})
 }
 
 // ProjectCreate creates all services but do not start them.
-func ProjectCreate(p project.APIProject, c *cli.Context) {
+func ProjectCreate(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	options := options.Create{
 		NoRecreate:    c.Bool("no-recreate"),
 		ForceRecreate: c.Bool("force-recreate"),
 		NoBuild:       c.Bool("no-build"),
 	}
-	err := p.Create(options, c.Args()...)
+	err := p.Create(ctx, options, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.create", map[string]interface{}{"services": c.Args()})
 }
 
 // ProjectUp brings all services up.
-func ProjectUp(p project.APIProject, c *cli.Context) {
-	options := options.Up{
+func ProjectUp(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	opts := options.Up{
 		Create: options.Create{
 			NoRecreate:    c.Bool("no-recreate"),
 			ForceRecreate: c.Bool("force-recreate"),
 			NoBuild:       c.Bool("no-build"),
 		},
 	}
-	err := p.Up(options, c.Args()...)
+	err := p.Up(ctx, opts, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.start", map[string]interface{}{"services": c.Args()})
 	if !c.Bool("d") {
-		signalChan := make(chan os.Signal, 1)
-		cleanupDone := make(chan bool)
-		signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
-		errChan := make(chan error)
-		go func() {
-			errChan <- p.Log(true, c.Args()...)
-		}()
-		go func() {
-			select {
-			case <-signalChan:
-				fmt.Printf("\nGracefully stopping...\n")
-				ProjectStop(p, c)
-				cleanupDone <- true
-			case err := <-errChan:
-				if err != nil {
-					logrus.Fatal(err)
-				}
-				cleanupDone <- true
+		err := p.Log(ctx, options.Log{Follow: true, Writer: output.Out()}, c.Args()...)
+		if err != nil && ctx.Err() == nil {
+			if isUnsupportedLogDriver(err) {
+				fmt.Fprintf(output.Err(), "Can't retrieve logs for one or more services: %v\n", err)
+			} else {
+				logrus.Fatal(err)
 			}
-		}()
-		<-cleanupDone
+		}
+		if ctx.Err() != nil {
+			// The context was canceled (Ctrl-C or --timeout); stop the
+			// services with a fresh context since ctx is already done.
+			ProjectStop(context.Background(), p, c, output)
+		}
 	}
 }
 
 // ProjectRun runs a given command within a service's container.
-func ProjectRun(p project.APIProject, c *cli.Context) {
+func ProjectRun(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	if len(c.Args()) == 1 {
 		logrus.Fatal("No service specified")
 	}
@@ -162,7 +196,7 @@ func ProjectRun(p project.APIProject, c *cli.Context) {
 	serviceName := c.Args()[0]
 	commandParts := c.Args()[1:]
 
-	exitCode, err := p.Run(serviceName, commandParts)
+	exitCode, err := p.Run(ctx, serviceName, commandParts)
 	if err != nil {
 		logrus.Fatal(err)
 	}
@@ -171,49 +205,64 @@ func ProjectRun(p project.APIProject, c *cli.Context) {
 }
 
 // ProjectStart starts services.
-func ProjectStart(p project.APIProject, c *cli.Context) {
-	err := p.Start(c.Args()...)
+func ProjectStart(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Start(ctx, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.start", map[string]interface{}{"services": c.Args()})
 }
 
 // ProjectRestart restarts services.
-func ProjectRestart(p project.APIProject, c *cli.Context) {
-	err := p.Restart(c.Int("timeout"), c.Args()...)
+func ProjectRestart(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Restart(ctx, c.Int("timeout"), c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 }
 
-// ProjectLog gets services logs.
-func ProjectLog(p project.APIProject, c *cli.Context) {
-	err := p.Log(c.Bool("follow"), c.Args()...)
+// ProjectLog gets services logs. A service configured with a log driver
+// that doesn't support reading back (none, journald, syslog, gelf, ...)
+// produces a warning instead of aborting the whole command.
+func ProjectLog(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	opts := options.Log{
+		Follow:      c.Bool("follow"),
+		Timestamps:  c.Bool("timestamps"),
+		NoLogPrefix: c.Bool("no-log-prefix"),
+		Writer:      output.Out(),
+	}
+	err := p.Log(ctx, opts, c.Args()...)
 	if err != nil {
+		if isUnsupportedLogDriver(err) {
+			fmt.Fprintf(output.Err(), "Can't retrieve logs for one or more services: %v\n", err)
+			return
+		}
 		logrus.Fatal(err)
 	}
 }
 
 // ProjectPull pulls images for services.
-func ProjectPull(p project.APIProject, c *cli.Context) {
-	err := p.Pull(c.Args()...)
+func ProjectPull(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	output.Event("pull.progress", map[string]interface{}{"services": c.Args(), "state": "start"})
+	err := p.Pull(ctx, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("pull.progress", map[string]interface{}{"services": c.Args(), "state": "done"})
 }
 
 // ProjectDelete deletes services.
-func ProjectDelete(p project.APIProject, c *cli.Context) {
-	stoppedContainers, err := p.ListStoppedContainers(c.Args()...)
+func ProjectDelete(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	stoppedContainers, err := p.ListStoppedContainers(ctx, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 	if len(stoppedContainers) == 0 {
-		fmt.Println("No stopped containers")
+		fmt.Fprintln(output.Out(), "No stopped containers")
 		return
 	}
 	if !c.Bool("force") {
-		fmt.Printf("Going to remove %v\nAre you sure? [yN]\n", strings.Join(stoppedContainers, ", "))
+		fmt.Fprintf(output.Out(), "Going to remove %v\nAre you sure? [yN]\n", strings.Join(stoppedContainers, ", "))
 		var answer string
 		_, err := fmt.Scanln(&answer)
 		if err != nil {
@@ -226,38 +275,39 @@ func ProjectDelete(p project.APIProject, c *cli.Context) {
 	options := options.Delete{
 		RemoveVolume: c.Bool("v"),
 	}
-	err = p.Delete(options, c.Args()...)
+	err = p.Delete(ctx, options, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 }
 
 // ProjectKill forces stop service containers.
-func ProjectKill(p project.APIProject, c *cli.Context) {
-	err := p.Kill(c.String("signal"), c.Args()...)
+func ProjectKill(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Kill(ctx, c.String("signal"), c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	output.Event("container.stop", map[string]interface{}{"services": c.Args()})
 }
 
 // ProjectPause pauses service containers.
-func ProjectPause(p project.APIProject, c *cli.Context) {
-	err := p.Pause(c.Args()...)
+func ProjectPause(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Pause(ctx, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 }
 
 // ProjectUnpause unpauses service containers.
-func ProjectUnpause(p project.APIProject, c *cli.Context) {
-	err := p.Unpause(c.Args()...)
+func ProjectUnpause(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	err := p.Unpause(ctx, c.Args()...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 }
 
 // ProjectScale scales services.
-func ProjectScale(p project.APIProject, c *cli.Context) {
+func ProjectScale(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
 	servicesScale := map[string]int{}
 	for _, arg := range c.Args() {
 		kv := strings.SplitN(arg, "=", 2)
@@ -275,7 +325,7 @@ func ProjectScale(p project.APIProject, c *cli.Context) {
 		servicesScale[name] = count
 	}
 
-	err := p.Scale(c.Int("timeout"), servicesScale)
+	err := p.Scale(ctx, c.Int("timeout"), servicesScale)
 	if err != nil {
 		logrus.Fatal(err)
 	}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project/options"
+)
+
+// BuildProgressPrinter is the project/options interface the build service
+// actually invokes; it's aliased here so the printer implementations below
+// read naturally as part of the CLI's --progress flag.
+type BuildProgressPrinter = options.BuildProgressPrinter
+
+// resolveBuildProgressPrinter maps the --progress flag value to a
+// BuildProgressPrinter writing to output.
+func resolveBuildProgressPrinter(mode string, output Output) BuildProgressPrinter {
+	switch mode {
+	case "tty":
+		return &ttyBuildProgressPrinter{out: output.Out()}
+	case "plain":
+		return &plainBuildProgressPrinter{out: output.Out()}
+	case "json":
+		return &jsonBuildProgressPrinter{out: output.Out()}
+	case "quiet":
+		return &quietBuildProgressPrinter{}
+	case "auto", "":
+		if isTerminal(output.Out()) {
+			return &ttyBuildProgressPrinter{out: output.Out()}
+		}
+		return &plainBuildProgressPrinter{out: output.Out()}
+	default:
+		logrus.Fatalf("Unknown --progress mode %q, must be one of auto, tty, plain, json, quiet", mode)
+		return nil
+	}
+}
+
+// isTerminal reports whether w looks like an interactive character device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ttyBuildProgressPrinter redraws the current step in place using ANSI
+// cursor movement, in the style of buildkit's tty progress output.
+type ttyBuildProgressPrinter struct {
+	out     io.Writer
+	lastLen int
+}
+
+func (t *ttyBuildProgressPrinter) redraw(line string) {
+	fmt.Fprintf(t.out, "\r\x1b[K%s", line)
+	t.lastLen = len(line)
+}
+
+func (t *ttyBuildProgressPrinter) Start(service string) {
+	fmt.Fprintf(t.out, "=> building %s\n", service)
+}
+
+func (t *ttyBuildProgressPrinter) Step(service, line string) {
+	t.redraw(fmt.Sprintf("=> [%s] %s", service, line))
+}
+
+func (t *ttyBuildProgressPrinter) Log(service, line string) {
+	t.redraw(fmt.Sprintf("=> [%s] %s", service, line))
+}
+
+func (t *ttyBuildProgressPrinter) Finish(service string) {
+	fmt.Fprintf(t.out, "\r\x1b[K=> [%s] done\n", service)
+}
+
+func (t *ttyBuildProgressPrinter) Error(service string, err error) {
+	fmt.Fprintf(t.out, "\r\x1b[K=> [%s] failed: %v\n", service, err)
+}
+
+// plainBuildProgressPrinter writes one line per event with no cursor
+// movement, suitable for CI logs.
+type plainBuildProgressPrinter struct {
+	out io.Writer
+}
+
+func (p *plainBuildProgressPrinter) Start(service string) {
+	fmt.Fprintf(p.out, "Building %s\n", service)
+}
+
+func (p *plainBuildProgressPrinter) Step(service, line string) {
+	fmt.Fprintf(p.out, "[%s] %s\n", service, line)
+}
+
+func (p *plainBuildProgressPrinter) Log(service, line string) {
+	fmt.Fprintf(p.out, "[%s] %s\n", service, line)
+}
+
+func (p *plainBuildProgressPrinter) Finish(service string) {
+	fmt.Fprintf(p.out, "Built %s\n", service)
+}
+
+func (p *plainBuildProgressPrinter) Error(service string, err error) {
+	fmt.Fprintf(p.out, "Failed building %s: %v\n", service, err)
+}
+
+// jsonBuildProgressPrinter emits one JSON object per event for machine
+// consumption.
+type jsonBuildProgressPrinter struct {
+	out io.Writer
+}
+
+type buildProgressEvent struct {
+	Service string `json:"service"`
+	State   string `json:"state"`
+	Line    string `json:"line,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (j *jsonBuildProgressPrinter) emit(e buildProgressEvent) {
+	json.NewEncoder(j.out).Encode(e)
+}
+
+func (j *jsonBuildProgressPrinter) Start(service string) {
+	j.emit(buildProgressEvent{Service: service, State: "start"})
+}
+
+func (j *jsonBuildProgressPrinter) Step(service, line string) {
+	j.emit(buildProgressEvent{Service: service, State: "step", Line: line})
+}
+
+func (j *jsonBuildProgressPrinter) Log(service, line string) {
+	j.emit(buildProgressEvent{Service: service, State: "log", Line: line})
+}
+
+func (j *jsonBuildProgressPrinter) Finish(service string) {
+	j.emit(buildProgressEvent{Service: service, State: "finish"})
+}
+
+func (j *jsonBuildProgressPrinter) Error(service string, err error) {
+	j.emit(buildProgressEvent{Service: service, State: "error", Error: err.Error()})
+}
+
+// quietBuildProgressPrinter discards all build progress.
+type quietBuildProgressPrinter struct{}
+
+func (quietBuildProgressPrinter) Start(service string)          {}
+func (quietBuildProgressPrinter) Step(service, line string)     {}
+func (quietBuildProgressPrinter) Log(service, line string)      {}
+func (quietBuildProgressPrinter) Finish(service string)         {}
+func (quietBuildProgressPrinter) Error(service string, _ error) {}
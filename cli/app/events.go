@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/project"
+)
+
+// eventsJSONRecord is the one-event-per-line record `events --json` writes.
+// It is encoded directly (not via Output.Event) so --json behaves the same
+// regardless of the global --format setting.
+type eventsJSONRecord struct {
+	Time      time.Time `json:"time"`
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Status    string    `json:"status"`
+}
+
+// ProjectEvents streams real-time docker events for the project's
+// containers until the context is canceled.
+func ProjectEvents(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	eventChan, err := p.Events(ctx, c.Args()...)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	jsonOut := c.Bool("json")
+	rawOut := output.Out()
+	if rw, ok := output.(RawWriter); ok {
+		rawOut = rw.Raw()
+	}
+	enc := json.NewEncoder(rawOut)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			if jsonOut {
+				enc.Encode(eventsJSONRecord{
+					Time:      evt.Time,
+					Service:   evt.Service,
+					Container: evt.Container,
+					Status:    evt.Status,
+				})
+				continue
+			}
+			fmt.Fprintf(output.Out(), "%s %-20s %-20s %s\n", evt.Time.Format(time.RFC3339), evt.Service, evt.Container, evt.Status)
+		}
+	}
+}
+
+// ProjectTop prints the table APIProject.Top returns. With the default
+// Project that is one row per container (service, name, status), not the
+// processes running inside it.
+func ProjectTop(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	info, err := p.Top(ctx, c.Args()...)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	fmt.Fprint(output.Out(), renderInfo(output.Format(), info.String(true)))
+}
+
+// ProjectImages lists the images used by each service.
+func ProjectImages(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	info, err := p.Images(ctx, c.Args()...)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	fmt.Fprint(output.Out(), renderInfo(output.Format(), info.String(true)))
+}
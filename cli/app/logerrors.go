@@ -0,0 +1,12 @@
+package app
+
+import "github.com/docker/libcompose/project"
+
+// isUnsupportedLogDriver reports whether err indicates the container's log
+// driver doesn't support reading logs back out (e.g. none, journald,
+// syslog, gelf). It defers to project.IsUnsupportedLogDriver, which is
+// also what Project.Log itself uses to decide whether to warn-and-continue
+// instead of failing.
+func isUnsupportedLogDriver(err error) bool {
+	return project.IsUnsupportedLogDriver(err)
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// OutputFormat selects how Project* actions render results and emit events.
+type OutputFormat string
+
+// Supported values for the --format global flag.
+const (
+	FormatPlain OutputFormat = "plain"
+	FormatTable OutputFormat = "table"
+	FormatJSON  OutputFormat = "json"
+)
+
+// ParseOutputFormat validates a --format flag value, defaulting to
+// FormatPlain when raw is empty.
+func ParseOutputFormat(raw string) (OutputFormat, error) {
+	switch OutputFormat(raw) {
+	case "":
+		return FormatPlain, nil
+	case FormatPlain, FormatTable, FormatJSON:
+		return OutputFormat(raw), nil
+	default:
+		return "", fmt.Errorf("unknown --format %q, must be one of json, table, plain", raw)
+	}
+}
+
+// Output is the set of writers and event sink that Project* actions use
+// instead of talking to os.Stdout/os.Stderr directly, so that libraries
+// embedding libcompose can redirect or structure CLI output without
+// hijacking global stdio.
+type Output interface {
+	// Out returns the writer for normal command output (e.g. a `ps` listing).
+	Out() io.Writer
+	// Err returns the writer for warnings and non-fatal error output.
+	Err() io.Writer
+	// Info returns the writer for informational progress messages.
+	Info() io.Writer
+	// Event emits a structured event, e.g. "container.create" or
+	// "build.progress". In plain/table mode it is rendered as an
+	// informational line; in json mode it is written as one
+	// newline-delimited JSON object directly to the output stream, not
+	// wrapped the way writes through Out are.
+	Event(name string, fields map[string]interface{})
+	// Format reports the OutputFormat this Output was built for, so
+	// callers rendering tabular data (e.g. renderInfo) know whether to
+	// align columns.
+	Format() OutputFormat
+}
+
+// RawWriter is implemented by Outputs that can hand back their underlying
+// writer, bypassing any format-specific wrapping. `events --json` needs
+// this: it builds its own JSON records and must not have them re-wrapped
+// by an Out() that already assumes plain text (as jsonOutput's does).
+type RawWriter interface {
+	Raw() io.Writer
+}
+
+// NewOutput creates an Output for format, writing to out and err. With
+// FormatJSON everything goes to out and err is unused.
+func NewOutput(format OutputFormat, out, err io.Writer) Output {
+	switch format {
+	case FormatJSON:
+		return &jsonOutput{out: out}
+	case FormatTable:
+		return &tableOutput{plainOutput{out: out, err: err}}
+	default:
+		return &plainOutput{out: out, err: err}
+	}
+}
+
+type plainOutput struct {
+	out, err io.Writer
+}
+
+func (p *plainOutput) Out() io.Writer       { return p.out }
+func (p *plainOutput) Err() io.Writer       { return p.err }
+func (p *plainOutput) Info() io.Writer      { return p.err }
+func (p *plainOutput) Raw() io.Writer       { return p.out }
+func (p *plainOutput) Format() OutputFormat { return FormatPlain }
+
+func (p *plainOutput) Event(name string, fields map[string]interface{}) {
+	msg, ok := eventMessage(name, fields)
+	if !ok {
+		msg = name
+	}
+	fmt.Fprintln(p.err, msg)
+}
+
+// eventMessage renders the known Event names as a human-readable line for
+// plain/table mode, using fields["services"] (a []string-like value, e.g.
+// cli.Args) and, for multi-step events, fields["state"] ("start"/"done").
+// It reports false for event names it doesn't recognize.
+func eventMessage(name string, fields map[string]interface{}) (string, bool) {
+	joined := "all services"
+	if services := stringSlice(fields["services"]); len(services) > 0 {
+		joined = strings.Join(services, ", ")
+	}
+	state, _ := fields["state"].(string)
+	switch name {
+	case "container.create":
+		return "Creating " + joined, true
+	case "container.start":
+		return "Starting " + joined, true
+	case "container.stop":
+		return "Stopping " + joined, true
+	case "build.progress":
+		if state == "done" {
+			return "Built " + joined, true
+		}
+		return "Building " + joined, true
+	case "pull.progress":
+		if state == "done" {
+			return "Pulled " + joined, true
+		}
+		return "Pulling " + joined, true
+	default:
+		return "", false
+	}
+}
+
+// stringSlice converts a []string-like value (including named slice types
+// such as cli.Args) to a []string, returning nil if v isn't one.
+func stringSlice(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	out := make([]string, rv.Len())
+	for i := range out {
+		out[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return out
+}
+
+// tableOutput behaves exactly like plainOutput except that it identifies
+// itself as FormatTable, so renderInfo aligns InfoSet columns with a
+// tabwriter instead of passing their tab-separated rows through as-is.
+type tableOutput struct {
+	plainOutput
+}
+
+func (t *tableOutput) Format() OutputFormat { return FormatTable }
+
+// renderInfo formats the tab-separated rows from an InfoSet.String() call
+// for display: FormatTable aligns them into columns with text/tabwriter,
+// any other format passes them through unchanged.
+func renderInfo(format OutputFormat, rows string) string {
+	if format != FormatTable {
+		return rows
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprint(w, rows)
+	w.Flush()
+	return buf.String()
+}
+
+// jsonEvent is the newline-delimited JSON record written for each Event
+// when the output format is json.
+type jsonEvent struct {
+	Time   time.Time              `json:"time"`
+	Event  string                 `json:"event"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// jsonOutput writes both command output and events as newline-delimited
+// JSON to a single stream, so scripts only need to read one pipe. Raw
+// writes through Out/Err/Info are themselves wrapped as JSON records
+// (rather than passed through verbatim) so the stream stays valid NDJSON
+// even for actions that were written against a plain text Output.
+type jsonOutput struct {
+	out io.Writer
+}
+
+func (j *jsonOutput) Out() io.Writer       { return &jsonLineWriter{out: j.out, stream: "stdout"} }
+func (j *jsonOutput) Err() io.Writer       { return &jsonLineWriter{out: j.out, stream: "stderr"} }
+func (j *jsonOutput) Info() io.Writer      { return &jsonLineWriter{out: j.out, stream: "info"} }
+func (j *jsonOutput) Raw() io.Writer       { return j.out }
+func (j *jsonOutput) Format() OutputFormat { return FormatJSON }
+
+func (j *jsonOutput) Event(name string, fields map[string]interface{}) {
+	json.NewEncoder(j.out).Encode(jsonEvent{
+		Time:   time.Now(),
+		Event:  name,
+		Fields: fields,
+	})
+}
+
+// jsonLine is the NDJSON record written for a raw write through
+// jsonLineWriter, e.g. a `ps` listing or a "Gracefully stopping..." notice.
+type jsonLine struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"`
+	Text   string    `json:"text"`
+}
+
+// jsonLineWriter adapts an io.Writer consumer expecting plain text into
+// one JSON object per Write call, tagged with which stream it came from.
+type jsonLineWriter struct {
+	out    io.Writer
+	stream string
+}
+
+func (w *jsonLineWriter) Write(p []byte) (int, error) {
+	err := json.NewEncoder(w.out).Encode(jsonLine{
+		Time:   time.Now(),
+		Stream: w.stream,
+		Text:   string(p),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
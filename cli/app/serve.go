@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/project/options"
+)
+
+// defaultScaleTimeoutSeconds is the container stop grace period /v1/scale
+// uses when the request doesn't ask for one, matching the `scale`
+// subcommand's own --timeout default.
+const defaultScaleTimeoutSeconds = 10
+
+// ProjectServe keeps the project loaded in memory and exposes it over a
+// REST API, so tools can drive libcompose without re-parsing the compose
+// file on every invocation. Each request is served with its own context,
+// canceled when the client disconnects, rather than the ctx WithProject
+// built for a single one-shot command.
+func ProjectServe(ctx context.Context, p project.APIProject, c *cli.Context, output Output) {
+	listen := c.String("listen")
+	if listen == "" {
+		listen = "127.0.0.1:0"
+	}
+
+	idleTimeout := time.Duration(c.Int("idle-timeout")) * time.Second
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		logrus.Fatalf("Failed to listen on %s: %v", listen, err)
+	}
+
+	server := &http.Server{Handler: newServeHandler(p, output)}
+	tracker := newIdleTracker(idleTimeout, server.Close)
+	server.Handler = tracker.wrap(server.Handler)
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	output.Event("server.listen", map[string]interface{}{"addr": listener.Addr().String()})
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		logrus.Fatalf("Server error: %v", err)
+	}
+}
+
+// idleTracker calls shutdown once no request has been active for longer
+// than timeout. A timeout of zero disables the idle shutdown. It depends
+// only on a shutdown func so it can be tested without a real listener.
+type idleTracker struct {
+	timeout  time.Duration
+	shutdown func()
+
+	mu       sync.Mutex
+	active   int
+	lastSeen time.Time
+	timer    *time.Timer
+}
+
+func newIdleTracker(timeout time.Duration, shutdown func()) *idleTracker {
+	t := &idleTracker{
+		timeout:  timeout,
+		shutdown: shutdown,
+		lastSeen: time.Now(),
+	}
+	if timeout > 0 {
+		t.timer = time.AfterFunc(timeout, t.checkIdle)
+	}
+	return t
+}
+
+func (t *idleTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.enter()
+		defer t.leave()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t *idleTracker) enter() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active++
+}
+
+func (t *idleTracker) leave() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	t.lastSeen = time.Now()
+	if t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+func (t *idleTracker) checkIdle() {
+	t.mu.Lock()
+	idle := t.active == 0 && time.Since(t.lastSeen) >= t.timeout
+	t.mu.Unlock()
+	if idle {
+		logrus.Info("Shutting down idle server")
+		t.shutdown()
+		return
+	}
+	t.timer.Reset(t.timeout)
+}
+
+// newServeHandler builds the /v1 REST API mux backed by p.
+func newServeHandler(p project.APIProject, output Output) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/up", serveJSON(output, func(r *http.Request) (interface{}, error) {
+		return nil, p.Up(r.Context(), options.Up{}, serviceNames(r)...)
+	}))
+	mux.HandleFunc("/v1/down", serveJSON(output, func(r *http.Request) (interface{}, error) {
+		return nil, p.Down(r.Context(), options.Down{}, serviceNames(r)...)
+	}))
+	mux.HandleFunc("/v1/ps", serveJSON(output, func(r *http.Request) (interface{}, error) {
+		return p.Ps(r.Context(), false, serviceNames(r)...)
+	}))
+	mux.HandleFunc("/v1/scale", serveJSON(output, func(r *http.Request) (interface{}, error) {
+		var scale map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&scale); err != nil {
+			return nil, err
+		}
+		timeout := defaultScaleTimeoutSeconds
+		if raw := r.URL.Query().Get("timeout"); raw != "" {
+			t, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout: %v", err)
+			}
+			timeout = t
+		}
+		return nil, p.Scale(r.Context(), timeout, scale)
+	}))
+	mux.HandleFunc("/v1/build", serveBuild(p))
+	mux.HandleFunc("/v1/logs", serveLogs(p))
+	return mux
+}
+
+// serviceNames reads the repeated "service" query parameter.
+func serviceNames(r *http.Request) []string {
+	return r.URL.Query()["service"]
+}
+
+// serveJSON adapts a handler that returns a result or error into an
+// http.HandlerFunc that renders the result (or error) as JSON.
+func serveJSON(output Output, fn func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := fn(r)
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if result == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// flushWriter flushes the underlying http.ResponseWriter after every write,
+// so a handler streaming a long-lived response (logs, build progress) is
+// actually delivered to the client incrementally instead of being buffered
+// until the handler returns.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// serveLogs streams service logs to the client as they are produced. The
+// log lines are written directly into the response via options.Log.Writer,
+// flushing after each write, rather than going to the daemon's own stdio.
+func serveLogs(p project.APIProject) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		opts := options.Log{
+			Follow:      r.URL.Query().Get("follow") == "true",
+			Timestamps:  r.URL.Query().Get("timestamps") == "true",
+			NoLogPrefix: r.URL.Query().Get("no-log-prefix") == "true",
+			Writer:      flushWriter{w},
+		}
+		if err := p.Log(r.Context(), opts, serviceNames(r)...); err != nil && !isUnsupportedLogDriver(err) {
+			fmt.Fprintf(w, "error: %v\n", err)
+		}
+	}
+}
+
+// serveBuild streams build progress to the client as JSON lines, writing
+// each event directly into the response rather than the daemon's own
+// stdio, so concurrent builds don't interleave on a shared stream.
+func serveBuild(p project.APIProject) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		build := options.Build{ProgressPrinter: &jsonBuildProgressPrinter{out: flushWriter{w}}}
+		if err := p.Build(r.Context(), build, serviceNames(r)...); err != nil {
+			json.NewEncoder(flushWriter{w}).Encode(map[string]string{"error": err.Error()})
+		}
+	}
+}
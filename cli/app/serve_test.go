@@ -0,0 +1,54 @@
+package app
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdleTrackerShutsDownAfterTimeout(t *testing.T) {
+	var shutdowns int32
+	newIdleTracker(20*time.Millisecond, func() {
+		atomic.AddInt32(&shutdowns, 1)
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&shutdowns) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&shutdowns); got != 1 {
+		t.Fatalf("shutdown called %d times, want 1", got)
+	}
+}
+
+func TestIdleTrackerResetsWhileActive(t *testing.T) {
+	var shutdowns int32
+	tracker := newIdleTracker(20*time.Millisecond, func() {
+		atomic.AddInt32(&shutdowns, 1)
+	})
+
+	stop := time.Now().Add(80 * time.Millisecond)
+	for time.Now().Before(stop) {
+		tracker.enter()
+		time.Sleep(5 * time.Millisecond)
+		tracker.leave()
+	}
+
+	if got := atomic.LoadInt32(&shutdowns); got != 0 {
+		t.Fatalf("shutdown called %d times while requests stayed active, want 0", got)
+	}
+}
+
+func TestIdleTrackerZeroTimeoutDisablesShutdown(t *testing.T) {
+	var shutdowns int32
+	newIdleTracker(0, func() {
+		atomic.AddInt32(&shutdowns, 1)
+	})
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&shutdowns); got != 0 {
+		t.Fatalf("shutdown called %d times with timeout disabled, want 0", got)
+	}
+}
@@ -0,0 +1,41 @@
+// Package project is the programmatic interface libcompose exposes for
+// driving a compose project: the CLI (cli/app) is one caller, but any Go
+// program can embed APIProject directly.
+package project
+
+import (
+	"context"
+
+	"github.com/docker/libcompose/project/options"
+)
+
+// APIProject is the set of operations a loaded compose project supports.
+// Every method takes a context.Context first so a caller can cancel or
+// time out an in-flight operation.
+type APIProject interface {
+	Create(ctx context.Context, options options.Create, services ...string) error
+	Build(ctx context.Context, options options.Build, services ...string) error
+	Up(ctx context.Context, options options.Up, services ...string) error
+	Down(ctx context.Context, options options.Down, services ...string) error
+	Delete(ctx context.Context, options options.Delete, services ...string) error
+	Start(ctx context.Context, services ...string) error
+	Stop(ctx context.Context, timeout int, services ...string) error
+	Restart(ctx context.Context, timeout int, services ...string) error
+	Kill(ctx context.Context, signal string, services ...string) error
+	Pause(ctx context.Context, services ...string) error
+	Unpause(ctx context.Context, services ...string) error
+	Pull(ctx context.Context, services ...string) error
+	Scale(ctx context.Context, timeout int, servicesScale map[string]int) error
+	Run(ctx context.Context, serviceName string, commandParts []string) (int, error)
+	Ps(ctx context.Context, onlyID bool, services ...string) (InfoSet, error)
+	Port(ctx context.Context, index int, protocol, serviceName, privatePort string) (string, error)
+	Log(ctx context.Context, options options.Log, services ...string) error
+	ListStoppedContainers(ctx context.Context, services ...string) ([]string, error)
+	Events(ctx context.Context, services ...string) (<-chan ContainerEvent, error)
+	Top(ctx context.Context, services ...string) (InfoSet, error)
+	Images(ctx context.Context, services ...string) (InfoSet, error)
+}
+
+// BuildProgressPrinter is re-exported from options, since the build
+// service (Project.Build) is what actually invokes it.
+type BuildProgressPrinter = options.BuildProgressPrinter
@@ -0,0 +1,12 @@
+package project
+
+import "time"
+
+// ContainerEvent is a single real-time docker event for one of the
+// project's containers, as streamed by APIProject.Events.
+type ContainerEvent struct {
+	Time      time.Time
+	Service   string
+	Container string
+	Status    string
+}
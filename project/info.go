@@ -0,0 +1,58 @@
+package project
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// Info is a single row of output, e.g. one container's ps/top/images
+// listing, keyed by column name.
+type Info map[string]string
+
+// InfoSet is a collection of Info rows rendered as a table by Ps, Top, and
+// Images.
+type InfoSet []Info
+
+// String renders the set as tab-separated text: one header row (when
+// withHeader is true) followed by one row per entry. Callers are free to
+// pass the result through a text/tabwriter (or not) depending on how they
+// want it displayed.
+func (infos InfoSet) String(withHeader bool) string {
+	if len(infos) == 0 {
+		return ""
+	}
+
+	keys := infos.columns()
+	var buf bytes.Buffer
+	if withHeader {
+		buf.WriteString(strings.Join(keys, "\t"))
+		buf.WriteString("\n")
+	}
+	for _, info := range infos {
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = info[key]
+		}
+		buf.WriteString(strings.Join(values, "\t"))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// columns returns every column name used across the set, in a stable
+// (alphabetical) order.
+func (infos InfoSet) columns() []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, info := range infos {
+		for key := range info {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,16 @@
+package project
+
+import "strings"
+
+// IsUnsupportedLogDriver reports whether err indicates a container's log
+// driver doesn't support reading logs back out (e.g. none, journald,
+// syslog, gelf), the client-side equivalent of errdefs.ErrNotImplemented
+// from the docker API.
+func IsUnsupportedLogDriver(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "not implemented") ||
+		strings.Contains(msg, "configured logging driver does not support reading")
+}
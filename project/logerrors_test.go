@@ -0,0 +1,27 @@
+package project
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsUnsupportedLogDriver(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not implemented", errors.New("not implemented"), true},
+		{"wrapped not implemented", errors.New("Error response from daemon: not implemented"), true},
+		{"unsupported driver message", errors.New("configured logging driver does not support reading"), true},
+		{"unrelated error", errors.New("no such container: web_1"), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsUnsupportedLogDriver(c.err); got != c.want {
+				t.Errorf("IsUnsupportedLogDriver(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package options holds the per-action option structs that APIProject
+// methods take, so that adding a flag doesn't change every method's
+// signature.
+package options
+
+import "io"
+
+// Create holds options for APIProject.Create.
+type Create struct {
+	NoRecreate    bool
+	ForceRecreate bool
+	NoBuild       bool
+}
+
+// Up holds options for APIProject.Up.
+type Up struct {
+	Create Create
+}
+
+// Down holds options for APIProject.Down.
+type Down struct {
+	RemoveVolume bool
+}
+
+// Delete holds options for APIProject.Delete.
+type Delete struct {
+	RemoveVolume bool
+}
+
+// BuildProgressPrinter is invoked by the build service as a build runs, so
+// that different front-ends (a human terminal, a CI log, a machine
+// consumer, or a caller's own implementation) can render the same
+// sequence of events their own way.
+type BuildProgressPrinter interface {
+	// Start is called once, before the first step of service's build.
+	Start(service string)
+	// Step is called for every build step, e.g. "Step 2/5 : RUN go build".
+	Step(service, line string)
+	// Log is called for every line of output a build step produces.
+	Log(service, line string)
+	// Finish is called once the service's image has been built successfully.
+	Finish(service string)
+	// Error is called instead of Finish when the build fails.
+	Error(service string, err error)
+}
+
+// Build holds options for APIProject.Build.
+type Build struct {
+	NoCache bool
+	// ProgressPrinter receives build events as each service image builds.
+	// Left nil, a build produces no progress output.
+	ProgressPrinter BuildProgressPrinter
+}
+
+// Log holds options for APIProject.Log.
+type Log struct {
+	Follow      bool
+	Timestamps  bool
+	NoLogPrefix bool
+	// Writer, when set, receives log lines instead of the process's own
+	// stdout, letting callers (the CLI's Output abstraction, the REST API)
+	// capture or redirect log output.
+	Writer io.Writer
+}
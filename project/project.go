@@ -0,0 +1,375 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/docker/libcompose/project/options"
+)
+
+// Project is the default APIProject implementation: it holds the set of
+// named Services a compose file describes and fans each operation out
+// across the selected subset of them.
+type Project struct {
+	Services []Service
+}
+
+// selected returns the named services, or every service when names is
+// empty (the convention every Project* CLI action relies on).
+func (p *Project) selected(names []string) []Service {
+	if len(names) == 0 {
+		return p.Services
+	}
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		want[name] = true
+	}
+	var out []Service
+	for _, s := range p.Services {
+		if want[s.Name()] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (p *Project) byName(name string) (Service, error) {
+	for _, s := range p.Services {
+		if s.Name() == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("no such service: %s", name)
+}
+
+// Create creates the selected services but does not start them.
+func (p *Project) Create(ctx context.Context, opts options.Create, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Create(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build builds the selected services, driving opts.ProgressPrinter (if
+// set) through Start/Finish/Error around each service's build.
+func (p *Project) Build(ctx context.Context, opts options.Build, services ...string) error {
+	for _, s := range p.selected(services) {
+		if opts.ProgressPrinter != nil {
+			opts.ProgressPrinter.Start(s.Name())
+		}
+		err := s.Build(ctx, opts)
+		if opts.ProgressPrinter != nil {
+			if err != nil {
+				opts.ProgressPrinter.Error(s.Name(), err)
+			} else {
+				opts.ProgressPrinter.Finish(s.Name())
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Up creates and starts the selected services.
+func (p *Project) Up(ctx context.Context, opts options.Up, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Up(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down stops and removes the selected services' containers.
+func (p *Project) Down(ctx context.Context, opts options.Down, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Down(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the selected services' stopped containers.
+func (p *Project) Delete(ctx context.Context, opts options.Delete, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Delete(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start starts the selected services' existing containers.
+func (p *Project) Start(ctx context.Context, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Start(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops the selected services, giving each container timeout seconds
+// to shut down on its own before killing it.
+func (p *Project) Stop(ctx context.Context, timeout int, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Stop(ctx, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restart restarts the selected services.
+func (p *Project) Restart(ctx context.Context, timeout int, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Restart(ctx, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kill forces the selected services' containers to stop.
+func (p *Project) Kill(ctx context.Context, signal string, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Kill(ctx, signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pause pauses the selected services' containers.
+func (p *Project) Pause(ctx context.Context, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Pause(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unpause unpauses the selected services' containers.
+func (p *Project) Unpause(ctx context.Context, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Unpause(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pull pulls the images for the selected services.
+func (p *Project) Pull(ctx context.Context, services ...string) error {
+	for _, s := range p.selected(services) {
+		if err := s.Pull(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scale scales each named service to its requested count.
+func (p *Project) Scale(ctx context.Context, timeout int, servicesScale map[string]int) error {
+	for name, count := range servicesScale {
+		s, err := p.byName(name)
+		if err != nil {
+			return err
+		}
+		if err := s.Scale(ctx, timeout, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run runs a one-off command inside serviceName's container.
+func (p *Project) Run(ctx context.Context, serviceName string, commandParts []string) (int, error) {
+	s, err := p.byName(serviceName)
+	if err != nil {
+		return -1, err
+	}
+	return s.Run(ctx, commandParts)
+}
+
+// Ps lists the selected services' containers.
+func (p *Project) Ps(ctx context.Context, onlyID bool, services ...string) (InfoSet, error) {
+	var infos InfoSet
+	for _, s := range p.selected(services) {
+		containers, err := s.Containers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			if onlyID {
+				infos = append(infos, Info{"ID": c.ID})
+				continue
+			}
+			infos = append(infos, Info{
+				"NAME":    c.Name,
+				"SERVICE": s.Name(),
+				"COMMAND": c.Command,
+				"STATE":   c.Status,
+				"PORTS":   c.Ports,
+			})
+		}
+	}
+	return infos, nil
+}
+
+// Port returns the Ports description of the index'th (zero-based) container
+// of serviceName. It does not pick out the binding for privatePort or
+// protocol; both are ignored.
+func (p *Project) Port(ctx context.Context, index int, protocol, serviceName, privatePort string) (string, error) {
+	s, err := p.byName(serviceName)
+	if err != nil {
+		return "", err
+	}
+	containers, err := s.Containers(ctx)
+	if err != nil {
+		return "", err
+	}
+	if index < 0 || index >= len(containers) {
+		return "", fmt.Errorf("no container at index %d for service %q", index, serviceName)
+	}
+	return containers[index].Ports, nil
+}
+
+// logWriter returns where Log should write lines for opts: the caller's
+// Writer if set, otherwise the process's own stdout.
+func logWriter(opts options.Log) io.Writer {
+	if opts.Writer != nil {
+		return opts.Writer
+	}
+	return os.Stdout
+}
+
+// Log streams the selected services' logs. A service whose log driver
+// doesn't support reading logs back out produces a warning on opts.Writer
+// and is skipped, rather than failing the whole command.
+func (p *Project) Log(ctx context.Context, opts options.Log, services ...string) error {
+	for _, s := range p.selected(services) {
+		err := s.Log(ctx, opts)
+		if err == nil {
+			continue
+		}
+		if IsUnsupportedLogDriver(err) {
+			fmt.Fprintf(logWriter(opts), "Can't retrieve logs for %q: %v\n", s.Name(), err)
+			continue
+		}
+		return err
+	}
+	return nil
+}
+
+// ListStoppedContainers lists the names of the selected services'
+// containers whose status is "exited" or "stopped".
+func (p *Project) ListStoppedContainers(ctx context.Context, services ...string) ([]string, error) {
+	var names []string
+	for _, s := range p.selected(services) {
+		containers, err := s.Containers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			if c.Status == "exited" || c.Status == "stopped" {
+				names = append(names, c.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// Events merges the selected services' real-time docker events into a
+// single channel, closed once every service's event stream has ended or
+// ctx is canceled.
+func (p *Project) Events(ctx context.Context, services ...string) (<-chan ContainerEvent, error) {
+	selected := p.selected(services)
+	sources := make([]<-chan ContainerEvent, 0, len(selected))
+	for _, s := range selected {
+		ch, err := s.Events(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, ch)
+	}
+
+	merged := make(chan ContainerEvent)
+	var wg sync.WaitGroup
+	for _, ch := range sources {
+		wg.Add(1)
+		go func(ch <-chan ContainerEvent) {
+			defer wg.Done()
+			for {
+				select {
+				case evt, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case merged <- evt:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged, nil
+}
+
+// Top lists the selected services' containers, one row per container with
+// its service, name and status. It does not run `docker top` and does not
+// list the processes inside the containers.
+func (p *Project) Top(ctx context.Context, services ...string) (InfoSet, error) {
+	var infos InfoSet
+	for _, s := range p.selected(services) {
+		containers, err := s.Containers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			infos = append(infos, Info{
+				"SERVICE":   s.Name(),
+				"CONTAINER": c.Name,
+				"STATUS":    c.Status,
+			})
+		}
+	}
+	return infos, nil
+}
+
+// Images lists the images backing the selected services' containers.
+func (p *Project) Images(ctx context.Context, services ...string) (InfoSet, error) {
+	var infos InfoSet
+	for _, s := range p.selected(services) {
+		containers, err := s.Containers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			infos = append(infos, Info{
+				"SERVICE":  s.Name(),
+				"IMAGE":    c.Image,
+				"IMAGE ID": c.ImageID,
+				"SIZE":     c.Size,
+			})
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,44 @@
+package project
+
+import (
+	"context"
+
+	"github.com/docker/libcompose/project/options"
+)
+
+// Service is the per-service backend that Project's orchestration methods
+// fan operations out to. A concrete implementation talks to the docker
+// API; this package only defines the contract and the fan-out above it.
+type Service interface {
+	Name() string
+	Create(ctx context.Context, options options.Create) error
+	Build(ctx context.Context, options options.Build) error
+	Up(ctx context.Context, options options.Up) error
+	Down(ctx context.Context, options options.Down) error
+	Delete(ctx context.Context, options options.Delete) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context, timeout int) error
+	Restart(ctx context.Context, timeout int) error
+	Kill(ctx context.Context, signal string) error
+	Pause(ctx context.Context) error
+	Unpause(ctx context.Context) error
+	Pull(ctx context.Context) error
+	Scale(ctx context.Context, timeout int, count int) error
+	Run(ctx context.Context, commandParts []string) (int, error)
+	Containers(ctx context.Context) ([]Container, error)
+	Log(ctx context.Context, options options.Log) error
+	Events(ctx context.Context) (<-chan ContainerEvent, error)
+}
+
+// Container describes a single running or stopped container backing a
+// Service, as surfaced by Ps, Top, and Images.
+type Container struct {
+	ID      string
+	Name    string
+	Command string
+	Status  string
+	Ports   string
+	Image   string
+	ImageID string
+	Size    string
+}